@@ -0,0 +1,90 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileName は設定ファイルの既定のファイル名です
+const configFileName = ".missio.yml"
+
+// PatternSet はファイル名・拡張子・パスによるマッチングルールの集合です
+type PatternSet struct {
+	Names      []string `yaml:"names"`
+	Extensions []string `yaml:"extensions"`
+	Paths      []string `yaml:"paths"`
+}
+
+// Config はmissioの設定ファイル（.missio.yml）の内容を保持します
+type Config struct {
+	Exclude PatternSet `yaml:"exclude"`
+	Include PatternSet `yaml:"include"`
+
+	// Signatures はコンテンツスキャンで使用するシグネチャ定義です
+	Signatures []SignatureRule `yaml:"signatures"`
+	// DeepScan はファイル名に関わらず内容スキャンを行うかどうかです
+	DeepScan bool `yaml:"deep_scan"`
+	// MaxFileSize はコンテンツスキャン対象とする最大ファイルサイズ（バイト）です。0の場合は既定値を使用します
+	MaxFileSize int64 `yaml:"max_file_size"`
+	// NoGitignore はtrueの場合、.gitignore/.dockerignore/.missioignoreを一切考慮しません
+	NoGitignore bool `yaml:"no_gitignore"`
+
+	// HiddenPolicy は隠しファイル・ディレクトリの扱い方です（"ignore" | "scan" | "only"）。未指定時は"scan"
+	HiddenPolicy string `yaml:"hidden_policy"`
+	// SymlinkPolicy はシンボリックリンクの辿り方です（"skip" | "follow" | "follow-same-fs"）。未指定時は"skip"
+	SymlinkPolicy string `yaml:"symlink_policy"`
+	// MaxSymlinkDepth はシンボリックリンクを辿る際の最大深さです。0の場合は既定値を使用します
+	MaxSymlinkDepth int `yaml:"max_symlink_depth"`
+}
+
+// LoadConfig はrootDir直下の設定ファイルを読み込みます。
+// ファイルが存在しない場合は既定の設定を返します。
+func LoadConfig(rootDir string) (*Config, error) {
+	config := defaultConfig()
+
+	path := filepath.Join(rootDir, configFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	} else {
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, err
+		}
+
+		// signatures:キー自体が無い場合にのみ既定シグネチャで補う。`signatures: []`は
+		// コンテンツスキャンを明示的に無効化する指定として区別する必要があるため、
+		// len(config.Signatures)==0では判定できずキーの有無を別途プローブする
+		var probe struct {
+			Signatures *[]SignatureRule `yaml:"signatures"`
+		}
+		if err := yaml.Unmarshal(data, &probe); err != nil {
+			return nil, err
+		}
+		if probe.Signatures == nil {
+			config.Signatures = defaultSignatures()
+		}
+	}
+
+	for i := range config.Signatures {
+		if err := config.Signatures[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// defaultConfig はmissio.ymlが存在しない場合の既定設定です
+func defaultConfig() *Config {
+	return &Config{
+		Include: PatternSet{
+			Names:      []string{".env", "credentials", "secret", "id_rsa", "id_dsa", "id_ecdsa", "id_ed25519"},
+			Extensions: []string{".pem", ".key", ".pfx", ".p12"},
+		},
+		Signatures: defaultSignatures(),
+	}
+}
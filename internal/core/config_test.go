@@ -0,0 +1,65 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadConfigDefaultSignaturesCompiled は.missio.ymlが存在しない場合でも
+// 既定シグネチャのcompiledが設定され、コンテンツスキャンに使用できることを確認します
+func TestLoadConfigDefaultSignaturesCompiled(t *testing.T) {
+	config, err := LoadConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if len(config.Signatures) == 0 {
+		t.Fatal("expected default signatures to be populated")
+	}
+
+	for _, sig := range config.Signatures {
+		if sig.Disabled || sig.Regex == "" {
+			continue
+		}
+		if sig.compiled == nil {
+			t.Errorf("signature %q: compiled is nil, content scanning would skip it", sig.Name)
+		}
+	}
+}
+
+// TestLoadConfigExplicitEmptySignaturesDisablesContentScan は`signatures: []`を
+// 明示した場合、既定シグネチャで補われず、コンテンツスキャンが完全に無効化されることを確認します
+func TestLoadConfigExplicitEmptySignaturesDisablesContentScan(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, configFileName), []byte("signatures: []\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	config, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if len(config.Signatures) != 0 {
+		t.Fatalf("expected explicit `signatures: []` to leave Signatures empty, got %d entries", len(config.Signatures))
+	}
+}
+
+// TestLoadConfigMissingSignaturesKeyUsesDefaults はsignatures:キー自体が
+// 無い場合には既定シグネチャが使われることを確認します
+func TestLoadConfigMissingSignaturesKeyUsesDefaults(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, configFileName), []byte("deep_scan: true\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	config, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if len(config.Signatures) != len(defaultSignatures()) {
+		t.Fatalf("expected default signatures when signatures: key is absent, got %d entries", len(config.Signatures))
+	}
+}
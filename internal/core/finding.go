@@ -0,0 +1,15 @@
+package core
+
+// Finding は走査によって検出された1件の秘匿情報候補です。
+// Ruleには発火したIncludeルールまたはシグネチャ名が入り、
+// Line/Matchはコンテンツスキャンによる検出の場合のみ設定されます。
+type Finding struct {
+	Path     string `json:"path"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Line     int    `json:"line,omitempty"`
+	Match    string `json:"match,omitempty"`
+}
+
+// defaultNameSeverity はファイル名・拡張子・パスパターンのみで検出した場合の既定の深刻度です
+const defaultNameSeverity = "medium"
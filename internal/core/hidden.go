@@ -0,0 +1,24 @@
+package core
+
+import "strings"
+
+// HiddenPolicy は隠しファイル・ディレクトリの扱い方です
+type HiddenPolicy string
+
+const (
+	// HiddenScan は隠しファイルも通常のファイルと同様に扱います（既定値）
+	HiddenScan HiddenPolicy = "scan"
+	// HiddenIgnore は隠しファイル・ディレクトリを走査対象から除外します
+	HiddenIgnore HiddenPolicy = "ignore"
+	// HiddenOnly は隠しファイルのみを走査対象とします（ディレクトリの走査自体は継続します）
+	HiddenOnly HiddenPolicy = "only"
+)
+
+// isHidden はabsPathが「隠し」ファイル・ディレクトリとみなされるかどうかを判定します。
+// Unix系では先頭ドットのみで判定し、Windowsではさらにhidden属性も確認します（isHiddenPlatform参照）。
+func isHidden(absPath, name string) bool {
+	if name != "." && name != ".." && strings.HasPrefix(name, ".") {
+		return true
+	}
+	return isHiddenPlatform(absPath)
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package core
+
+// isHiddenPlatform はUnix系では常にfalseを返します。先頭ドットの判定だけで十分なためです
+func isHiddenPlatform(absPath string) bool {
+	return false
+}
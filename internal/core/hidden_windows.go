@@ -0,0 +1,21 @@
+//go:build windows
+
+package core
+
+import "syscall"
+
+// isHiddenPlatform はWindowsのFILE_ATTRIBUTE_HIDDEN属性を確認します。
+// ドット始まりでなくても「隠しファイル」にされているファイルを拾うためです
+func isHiddenPlatform(absPath string) bool {
+	pointer, err := syscall.UTF16PtrFromString(absPath)
+	if err != nil {
+		return false
+	}
+
+	attrs, err := syscall.GetFileAttributes(pointer)
+	if err != nil {
+		return false
+	}
+
+	return attrs&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+}
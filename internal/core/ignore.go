@@ -0,0 +1,129 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+// ignoreFileNames はディレクトリごとに探索する無視ファイルの候補です
+var ignoreFileNames = []string{".gitignore", ".dockerignore", ".missioignore"}
+
+// ignoreLayer はあるディレクトリで読み込んだ無視ルールの1層です。パターンは
+// rootDir相対に書き換え済みで、rebuildCombinedIgnoreでroot→leaf順に連結されます。
+type ignoreLayer struct {
+	dir   string
+	lines []string
+}
+
+// loadIgnoreLayer はdir直下の無視ファイル（.gitignore/.dockerignore/.missioignore）を
+// 読み込み、各行をrootDir相対のパターンに書き換えます。対象ファイルが1つも無い場合はnilを返します。
+func loadIgnoreLayer(rootDir, dir string) (*ignoreLayer, error) {
+	var lines []string
+	for _, name := range ignoreFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if rewritten, ok := rewriteIgnoreLine(rootDir, dir, line); ok {
+				lines = append(lines, rewritten)
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	return &ignoreLayer{dir: dir, lines: lines}, nil
+}
+
+// rewriteIgnoreLine はdir直下の無視ファイルの1行を、rootDirを基準としたパターンに
+// 書き換えます。空行・コメント行はok=falseを返します。否定(!)や先頭の/によるアンカーは
+// 保持したまま、パターン自体をdirのrootDirからの相対パスの下に付け替えます。
+func rewriteIgnoreLine(rootDir, dir, line string) (string, bool) {
+	trimmed := strings.TrimRight(line, "\r")
+	pattern := strings.TrimSpace(trimmed)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return "", false
+	}
+
+	negate := strings.HasPrefix(pattern, "!")
+	if negate {
+		pattern = pattern[1:]
+	}
+
+	relDir, err := filepath.Rel(rootDir, dir)
+	if err != nil {
+		relDir = ""
+	}
+	relDir = filepath.ToSlash(relDir)
+	if relDir == "." {
+		relDir = ""
+	}
+
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var rewritten string
+	if anchored || strings.Contains(pattern, "/") {
+		// アンカーされたパターン、またはスラッシュを含むパターンはdir相対の位置に固定する
+		if relDir == "" {
+			rewritten = pattern
+		} else {
+			rewritten = relDir + "/" + pattern
+		}
+	} else {
+		// アンカーなしの単純なパターンはdir以下のどの深さにもマッチしうる
+		if relDir == "" {
+			rewritten = "**/" + pattern
+		} else {
+			rewritten = relDir + "/**/" + pattern
+		}
+	}
+	rewritten = "/" + rewritten
+
+	if negate {
+		rewritten = "!" + rewritten
+	}
+	return rewritten, true
+}
+
+// rebuildCombinedIgnore はスタック上の全層のパターンをroot→leaf順に連結し、
+// 1つのGitIgnoreとしてコンパイルします。gitの仕様どおり、より深い（より後に積まれた）
+// 層のパターンほど後に評価されるため、リーフの否定(!pattern)がルートの無視ルールを上書きできます。
+func rebuildCombinedIgnore(stack []ignoreLayer) *gitignore.GitIgnore {
+	var lines []string
+	for _, layer := range stack {
+		lines = append(lines, layer.lines...)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return gitignore.CompileIgnoreLines(lines...)
+}
+
+// isIgnored はcombinedをrootDir相対のabsPathに対して評価します
+func isIgnored(combined *gitignore.GitIgnore, rootDir, absPath string) bool {
+	if combined == nil {
+		return false
+	}
+	relPath, err := filepath.Rel(rootDir, absPath)
+	if err != nil {
+		return false
+	}
+	return combined.MatchesPath(filepath.ToSlash(relPath))
+}
+
+// isAncestorDir はancestorがdir自身かdirの祖先ディレクトリであるかを判定します
+func isAncestorDir(ancestor, dir string) bool {
+	if ancestor == dir {
+		return true
+	}
+	return strings.HasPrefix(dir, ancestor+string(filepath.Separator))
+}
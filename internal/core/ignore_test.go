@@ -0,0 +1,42 @@
+package core
+
+import "testing"
+
+// TestIsIgnoredLeafNegationOverridesRoot はgitの仕様どおり、より深い層の否定(!pattern)が
+// より浅い層の無視ルールを上書きできることを確認します
+func TestIsIgnoredLeafNegationOverridesRoot(t *testing.T) {
+	root := t.TempDir()
+
+	rootLayer, err := loadIgnoreLayer(root, root)
+	if err != nil {
+		t.Fatalf("loadIgnoreLayer(root): %v", err)
+	}
+	_ = rootLayer // root自体に無視ファイルは置かないため常にnil
+
+	rootLines, ok := rewriteIgnoreLine(root, root, "*.log")
+	if !ok {
+		t.Fatal("expected rewriteIgnoreLine to accept *.log")
+	}
+	stack := []ignoreLayer{
+		{dir: root, lines: []string{rootLines}},
+	}
+
+	subDir := root + "/sub"
+	subLines, ok := rewriteIgnoreLine(root, subDir, "!important.log")
+	if !ok {
+		t.Fatal("expected rewriteIgnoreLine to accept !important.log")
+	}
+	stack = append(stack, ignoreLayer{dir: subDir, lines: []string{subLines}})
+
+	combined := rebuildCombinedIgnore(stack)
+
+	ignoredLog := subDir + "/other.log"
+	if !isIgnored(combined, root, ignoredLog) {
+		t.Errorf("expected %s to be ignored via root *.log rule", ignoredLog)
+	}
+
+	unignoredLog := subDir + "/important.log"
+	if isIgnored(combined, root, unignoredLog) {
+		t.Errorf("expected %s to be un-ignored by the leaf negation", unignoredLog)
+	}
+}
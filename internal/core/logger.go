@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// Logger はスキャンの進捗とサマリーを標準エラー出力に書き出します
+type Logger struct {
+	rootDir  string
+	verbose  bool
+	maxDepth int
+	scanned  int64
+}
+
+// NewLogger は新しいLoggerインスタンスを作成します
+func NewLogger(rootDir string, verbose bool, maxDepth int) *Logger {
+	return &Logger{
+		rootDir:  rootDir,
+		verbose:  verbose,
+		maxDepth: maxDepth,
+	}
+}
+
+// IncrementScanned はスキャン済みファイル数をインクリメントします
+func (l *Logger) IncrementScanned() {
+	atomic.AddInt64(&l.scanned, 1)
+}
+
+// LogProgress はverboseモードの場合、現在処理中のパスを出力します
+func (l *Logger) LogProgress(path string) {
+	if !l.verbose {
+		return
+	}
+
+	relPath, err := filepath.Rel(l.rootDir, path)
+	if err != nil {
+		relPath = path
+	}
+
+	if l.maxDepth > 0 && strings.Count(relPath, string(filepath.Separator)) > l.maxDepth {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "scanning: %s\n", relPath)
+}
+
+// LogSummary はスキャン結果のサマリーを出力します
+func (l *Logger) LogSummary(findings []Finding) {
+	files := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		files[f.Path] = true
+	}
+	fmt.Fprintf(os.Stderr, "%d files scanned, %d secret files found (%d findings)\n", atomic.LoadInt64(&l.scanned), len(files), len(findings))
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// regexPatternPrefix はIncludeやExcludeのPathsの1エントリを正規表現として扱うための接頭辞です
+const regexPatternPrefix = "re:"
+
+// patternKind はpathMatcherがどの方式で評価されるかを表します
+type patternKind int
+
+const (
+	patternLiteral patternKind = iota
+	patternGlob
+	patternDoublestar
+	patternRegex
+)
+
+// pathMatcher はInclude/Exclude.Pathsの1パターンをコンパイルした述語です
+type pathMatcher struct {
+	kind  patternKind
+	raw   string // 元のパターン文字列（ログ・レポート表示用）
+	glob  string
+	regex *regexp.Regexp
+}
+
+// compilePathMatcher はパターン文字列を見て、リテラル・glob・doublestar・regexの
+// いずれかの述語としてコンパイルします。`**`を含む場合はdoublestar、
+// `re:`接頭辞がある場合はRE2正規表現として扱います。
+func compilePathMatcher(pattern string) (*pathMatcher, error) {
+	if strings.HasPrefix(pattern, regexPatternPrefix) {
+		expr := strings.TrimPrefix(pattern, regexPatternPrefix)
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		return &pathMatcher{kind: patternRegex, raw: pattern, regex: re}, nil
+	}
+
+	if strings.Contains(pattern, "**") {
+		return &pathMatcher{kind: patternDoublestar, raw: pattern, glob: pattern}, nil
+	}
+
+	if strings.ContainsAny(pattern, "*?[") {
+		return &pathMatcher{kind: patternGlob, raw: pattern, glob: pattern}, nil
+	}
+
+	return &pathMatcher{kind: patternLiteral, raw: pattern, glob: pattern}, nil
+}
+
+// compilePathMatchers はパターンのスライスをまとめてコンパイルします
+func compilePathMatchers(patterns []string) ([]*pathMatcher, error) {
+	matchers := make([]*pathMatcher, 0, len(patterns))
+	for _, pattern := range patterns {
+		m, err := compilePathMatcher(pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// Match はスラッシュ区切りの相対パス（小文字化済み）に対してマッチするかを判定します
+func (m *pathMatcher) Match(slashPath string) bool {
+	switch m.kind {
+	case patternRegex:
+		return m.regex.MatchString(slashPath)
+	case patternDoublestar:
+		matched, _ := doublestar.Match(m.glob, slashPath)
+		return matched
+	default: // patternGlob, patternLiteral
+		// `**`を持たない単純なglob/リテラルは、従来どおり各サブパスに対して
+		// filepath.Matchを試す（例: ".kamal/*" は "project/.kamal/secrets" にもマッチする）
+		return matchPathPattern(m.glob, filepath.FromSlash(slashPath))
+	}
+}
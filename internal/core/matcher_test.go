@@ -0,0 +1,35 @@
+package core
+
+import "testing"
+
+// TestPathMatcherDoublestar は`**`を含むパターンがdoublestarとして
+// 任意の深さのディレクトリにマッチすることを確認します
+func TestPathMatcherDoublestar(t *testing.T) {
+	m, err := compilePathMatcher("**/secrets/*.yml")
+	if err != nil {
+		t.Fatalf("compilePathMatcher: %v", err)
+	}
+
+	if !m.Match("a/b/secrets/prod.yml") {
+		t.Error("expected doublestar pattern to match nested path")
+	}
+	if m.Match("a/b/secrets/prod.yml.bak") {
+		t.Error("expected doublestar pattern not to match unrelated suffix")
+	}
+}
+
+// TestPathMatcherRegex は`re:`接頭辞付きのパターンがRE2正規表現として
+// 評価されることを確認します
+func TestPathMatcherRegex(t *testing.T) {
+	m, err := compilePathMatcher(`re:^config/.*\.secret$`)
+	if err != nil {
+		t.Fatalf("compilePathMatcher: %v", err)
+	}
+
+	if !m.Match("config/db.secret") {
+		t.Error("expected regex pattern to match")
+	}
+	if m.Match("other/db.secret") {
+		t.Error("expected regex pattern not to match outside config/")
+	}
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Reporter はFindingの一覧を指定した形式でwに書き出します
+type Reporter interface {
+	Report(w io.Writer, findings []Finding) error
+}
+
+// TextReporter はプレーンテキストでFindingを出力します（従来のCLI出力と同等の形式）
+type TextReporter struct{}
+
+// Report はfindingsを1行1件のプレーンテキストとして書き出します
+func (TextReporter) Report(w io.Writer, findings []Finding) error {
+	for _, f := range findings {
+		if f.Line > 0 {
+			if _, err := fmt.Fprintf(w, "%s:%d [%s] %s\n", f.Path, f.Line, f.Severity, f.Rule); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s [%s] %s\n", f.Path, f.Severity, f.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSONReporter はfindingsをJSON配列として出力します
+type JSONReporter struct{}
+
+// Report はfindingsをインデント付きJSONとして書き出します
+func (JSONReporter) Report(w io.Writer, findings []Finding) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+// sarifSchemaURI はSARIF 2.1.0の公式スキーマURLです
+const sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// sarifToolName はSARIFのtool.driver.nameに設定する名前です
+const sarifToolName = "missio"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIFReporter はfindingsをSARIF 2.1.0形式で出力します。Configのsignaturesから
+// tool.driver.rulesを埋めるため、GitHub code scanning等のSARIFコンシューマにそのまま渡せます
+type SARIFReporter struct {
+	Config *Config
+}
+
+// Report はfindingsをSARIF 2.1.0のJSONとして書き出します
+func (r SARIFReporter) Report(w io.Writer, findings []Finding) error {
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:  sarifToolName,
+						Rules: sarifRulesFromConfig(r.Config),
+					},
+				},
+				Results: sarifResultsFromFindings(findings),
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+func sarifRulesFromConfig(config *Config) []sarifRule {
+	if config == nil {
+		return nil
+	}
+	rules := make([]sarifRule, 0, len(config.Signatures))
+	for _, sig := range config.Signatures {
+		rules = append(rules, sarifRule{ID: sig.Name, Name: sig.Name})
+	}
+	return rules
+}
+
+func sarifResultsFromFindings(findings []Finding) []sarifResult {
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.Path)},
+			},
+		}
+		if f.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    f.Rule,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: fmt.Sprintf("%s matched rule %q", f.Path, f.Rule)},
+			Locations: []sarifLocation{location},
+		})
+	}
+	return results
+}
+
+// sarifLevel はmissioのSeverityをSARIFのlevel（error/warning/note）に変換します
+func sarifLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// severityRank はExceedsSeverityでの比較に使う深刻度の順位です
+var severityRank = map[string]int{"low": 1, "medium": 2, "high": 3}
+
+// ExceedsSeverity はfindingsの中にthreshold以上の深刻度のものが1件でもあればtrueを返します。
+// `--fail-on high`のようなCLIフラグから、終了コードを深刻度でゲートするために使われます
+func ExceedsSeverity(findings []Finding, threshold string) bool {
+	th, ok := severityRank[strings.ToLower(threshold)]
+	if !ok {
+		th = severityRank["low"]
+	}
+	for _, f := range findings {
+		if severityRank[strings.ToLower(f.Severity)] >= th {
+			return true
+		}
+	}
+	return false
+}
@@ -1,16 +1,94 @@
 package core
 
 import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+
+	gitignore "github.com/sabhiram/go-gitignore"
 )
 
+// binarySniffLen はバイナリ判定のために先頭から読み込むバイト数です
+const binarySniffLen = 8192
+
+// defaultMaxFileSize はmax_file_sizeが未設定の場合のコンテンツスキャン対象上限（バイト）です
+const defaultMaxFileSize = 5 * 1024 * 1024
+
+// maxLineBufferSize はコンテンツスキャンで1行として読み込む最大バイト数です
+const maxLineBufferSize = 1024 * 1024
+
+// defaultMaxSymlinkDepth はmax_symlink_depthが未設定の場合のシンボリックリンク追跡の最大深さです
+const defaultMaxSymlinkDepth = 40
+
 // Scanner は指定されたディレクトリ内の秘匿ファイルを検出します
 type Scanner struct {
 	rootDir string
 	logger  *Logger
 	config  *Config
+
+	// includePathMatchers/excludePathMatchers はInclude/Exclude.Pathsを
+	// NewScanner時に一度だけコンパイルした述語です
+	includePathMatchers []*pathMatcher
+	excludePathMatchers []*pathMatcher
+
+	// ignoreStack は.gitignore等から読み込んだ無視ルールのスタックです。
+	// ディレクトリに入るたびに積み、そのディレクトリを抜けたら取り除きます。
+	// walk()を行うゴルーチンのみが読み書きするため、ロックは不要です
+	ignoreStack []ignoreLayer
+
+	// combinedIgnore はignoreStackの全層をroot→leaf順に連結してコンパイルした
+	// 単一のGitIgnoreです。ignoreStackが変化するたびに再構築します
+	combinedIgnore *gitignore.GitIgnore
+
+	// concurrency はファイル判定・コンテンツスキャンを並列に行うワーカー数です
+	concurrency int
+
+	// hiddenPolicy/symlinkPolicy は隠しファイルとシンボリックリンクの扱い方です
+	hiddenPolicy    HiddenPolicy
+	symlinkPolicy   SymlinkPolicy
+	maxSymlinkDepth int
+
+	// visited はSymlinkFollow(SameFS)時に辿ったディレクトリのfileKeyの集合で、循環を防ぎます。
+	// walk()を行うゴルーチンのみが読み書きするため、ロックは不要です
+	visited map[fileKey]bool
+}
+
+// SetNoGitignore は.gitignore/.dockerignore/.missioignoreの考慮を無効にします。
+// CLIの--no-gitignoreフラグから呼び出されることを想定しています。
+func (s *Scanner) SetNoGitignore(v bool) {
+	s.config.NoGitignore = v
+}
+
+// Option はNewScannerの追加設定を行う関数です
+type Option func(*Scanner)
+
+// WithConcurrency はisSecretFile/コンテンツスキャンを処理するワーカー数を指定します。
+// 指定しない場合はruntime.GOMAXPROCS(0)が使われます
+func WithConcurrency(n int) Option {
+	return func(s *Scanner) {
+		s.concurrency = n
+	}
+}
+
+// WithHiddenPolicy は隠しファイル・ディレクトリの扱い方を指定します。指定しない場合はHiddenScanです
+func WithHiddenPolicy(p HiddenPolicy) Option {
+	return func(s *Scanner) {
+		s.hiddenPolicy = p
+	}
+}
+
+// WithSymlinkPolicy はシンボリックリンクの辿り方を指定します。指定しない場合はSymlinkSkipです
+func WithSymlinkPolicy(p SymlinkPolicy) Option {
+	return func(s *Scanner) {
+		s.symlinkPolicy = p
+	}
 }
 
 // 除外するディレクトリ名のリスト
@@ -34,111 +112,289 @@ var excludeDirs = []string{
 }
 
 // NewScanner は新しいScannerインスタンスを作成します
-func NewScanner(rootDir string, verbose bool, maxDepth int) (*Scanner, error) {
+func NewScanner(rootDir string, verbose bool, maxDepth int, opts ...Option) (*Scanner, error) {
 	// 設定ファイルを読み込む
 	config, err := LoadConfig(rootDir)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Scanner{
-		rootDir: rootDir,
-		logger:  NewLogger(rootDir, verbose, maxDepth),
-		config:  config,
-	}, nil
+	includeMatchers, err := compilePathMatchers(config.Include.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeMatchers, err := compilePathMatchers(config.Exclude.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scanner{
+		rootDir:             rootDir,
+		logger:              NewLogger(rootDir, verbose, maxDepth),
+		config:              config,
+		includePathMatchers: includeMatchers,
+		excludePathMatchers: excludeMatchers,
+		hiddenPolicy:        HiddenPolicy(config.HiddenPolicy),
+		symlinkPolicy:       SymlinkPolicy(config.SymlinkPolicy),
+		maxSymlinkDepth:     config.MaxSymlinkDepth,
+		visited:             make(map[fileKey]bool),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.concurrency <= 0 {
+		s.concurrency = runtime.GOMAXPROCS(0)
+	}
+	if s.hiddenPolicy == "" {
+		s.hiddenPolicy = HiddenScan
+	}
+	if s.symlinkPolicy == "" {
+		s.symlinkPolicy = SymlinkSkip
+	}
+	if s.maxSymlinkDepth <= 0 {
+		s.maxSymlinkDepth = defaultMaxSymlinkDepth
+	}
+
+	return s, nil
+}
+
+// Scan はディレクトリを走査し、検出結果をFindingのリストとして返します。
+// 走査自体はwalk()が単一ゴルーチンで行い、isSecretFile/コンテンツスキャンは
+// s.concurrency個のワーカーに分散します
+func (s *Scanner) Scan() ([]Finding, error) {
+	paths := make(chan string, s.concurrency*4)
+	outcomes := make(chan []Finding, s.concurrency*4)
+
+	var walkErr error
+	go func() {
+		defer close(paths)
+		walkErr = s.walk(paths)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(s.concurrency)
+	for i := 0; i < s.concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			s.worker(paths, outcomes)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	var findings []Finding
+	for outcome := range outcomes {
+		findings = append(findings, outcome...)
+	}
+
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	// ワーカー間の処理順序に依存しないよう、出力は決定的にソートする
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Line < findings[j].Line
+	})
+
+	s.logger.LogSummary(findings)
+	return findings, nil
 }
 
-// Scan はディレクトリを走査し、秘匿ファイルのリストを返します
-func (s *Scanner) Scan() ([]string, error) {
-	var files []string
+// ScanPaths は後方互換のため、検出した秘匿ファイルのパスのみを重複なく返します
+func (s *Scanner) ScanPaths() ([]string, error) {
+	findings, err := s.Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(findings))
+	paths := make([]string, 0, len(findings))
+	for _, f := range findings {
+		if seen[f.Path] {
+			continue
+		}
+		seen[f.Path] = true
+		paths = append(paths, f.Path)
+	}
+	return paths, nil
+}
 
-	err := filepath.Walk(s.rootDir, func(path string, info os.FileInfo, err error) error {
+// walk はfs.WalkDirでディレクトリ木を辿り、除外ディレクトリと無視ルールで
+// プルーニングしながら候補ファイルのパスをpathsに送出します
+func (s *Scanner) walk(paths chan<- string) error {
+	return filepath.WalkDir(s.rootDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() {
+		dir := path
+		if !d.IsDir() {
+			dir = filepath.Dir(path)
+		}
+
+		// このパスの祖先ではなくなった無視ルール層をスタックから外す
+		popped := false
+		for len(s.ignoreStack) > 0 && !isAncestorDir(s.ignoreStack[len(s.ignoreStack)-1].dir, dir) {
+			s.ignoreStack = s.ignoreStack[:len(s.ignoreStack)-1]
+			popped = true
+		}
+		if popped {
+			s.combinedIgnore = rebuildCombinedIgnore(s.ignoreStack)
+		}
+
+		if !s.config.NoGitignore && path != s.rootDir && isIgnored(s.combinedIgnore, s.rootDir, path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		hidden := path != s.rootDir && isHidden(path, d.Name())
+		if s.hiddenPolicy == HiddenIgnore && hidden {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
 			// 除外ディレクトリをスキップ
-			for _, dir := range excludeDirs {
-				if info.Name() == dir {
-					return filepath.SkipDir
+			if isExcludedDir(d.Name()) {
+				return filepath.SkipDir
+			}
+
+			if !s.config.NoGitignore {
+				layer, err := loadIgnoreLayer(s.rootDir, path)
+				if err != nil {
+					return err
+				}
+				if layer != nil {
+					s.ignoreStack = append(s.ignoreStack, *layer)
+					s.combinedIgnore = rebuildCombinedIgnore(s.ignoreStack)
 				}
 			}
+
 			s.logger.LogProgress(path)
 			return nil
 		}
 
+		// fs.WalkDirはシンボリックリンクを自動的には辿らないため、
+		// follow/follow-same-fsの場合はリンク先のディレクトリを手動で走査する
+		if d.Type()&fs.ModeSymlink != 0 && s.symlinkPolicy != SymlinkSkip {
+			target, statErr := os.Stat(path)
+			if statErr == nil && target.IsDir() {
+				return s.walkSymlinkDir(path, target, paths, 0)
+			}
+		}
+
+		if s.hiddenPolicy == HiddenOnly && !hidden {
+			return nil
+		}
+
+		paths <- path
+		return nil
+	})
+}
+
+// worker はpathsから候補ファイルを受け取り、isSecretFile判定とコンテンツスキャンを行い、
+// 秘匿ファイルと判定されたものをFindingとしてoutcomesに送出します
+func (s *Scanner) worker(paths <-chan string, outcomes chan<- []Finding) {
+	for path := range paths {
 		s.logger.IncrementScanned()
 		s.logger.LogProgress(path)
 
-		// パスを相対パスに変換
 		relPath, err := filepath.Rel(s.rootDir, path)
 		if err != nil {
-			return err
+			continue
 		}
 
 		// 秘匿ファイルかどうかをチェック（パターンマッチ優先）
-		if s.isSecretFile(relPath) {
-			files = append(files, relPath)
+		nameMatch, rule := s.isSecretFile(relPath)
+
+		var findings []Finding
+		// 名前でヒットしなくても、deep_scanが有効ならコンテンツを確認する
+		if nameMatch || s.config.DeepScan {
+			if contentFindings, err := s.scanContent(path, relPath); err == nil && len(contentFindings) > 0 {
+				for _, cf := range contentFindings {
+					findings = append(findings, Finding{
+						Path:     cf.Path,
+						Rule:     cf.Rule,
+						Severity: severityForRule(s.config.Signatures, cf.Rule),
+						Line:     cf.Line,
+						Match:    cf.Match,
+					})
+				}
+				nameMatch = true
+			}
 		}
 
-		return nil
-	})
+		if nameMatch && len(findings) == 0 {
+			findings = []Finding{{Path: relPath, Rule: rule, Severity: defaultNameSeverity}}
+		}
 
-	if err != nil {
-		return nil, err
+		if len(findings) > 0 {
+			outcomes <- findings
+		}
 	}
-
-	s.logger.LogSummary(files)
-	return files, nil
 }
 
-// isSecretFile はファイルが秘匿情報を含むかどうかを判定します
-func (s *Scanner) isSecretFile(relPath string) bool {
+// isSecretFile はファイルが秘匿情報を含むかどうかを判定します。
+// マッチした場合、どのIncludeルールが発火したかを示すラベルも返します
+func (s *Scanner) isSecretFile(relPath string) (bool, string) {
 	filename := filepath.Base(relPath)
 	ext := filepath.Ext(relPath)
 	lowerFilename := strings.ToLower(filename)
 	lowerPath := strings.ToLower(relPath)
+	lowerSlashPath := filepath.ToSlash(lowerPath)
 
 	// 除外パターンをチェック
 	for _, pattern := range s.config.Exclude.Names {
 		if strings.Contains(lowerFilename, strings.ToLower(pattern)) {
-			return false
+			return false, ""
 		}
 	}
 
 	for _, pattern := range s.config.Exclude.Extensions {
 		if strings.EqualFold(ext, pattern) {
-			return false
+			return false, ""
 		}
 	}
 
-	for _, pattern := range s.config.Exclude.Paths {
-		if matchPathPattern(pattern, lowerPath) {
-			return false
+	for _, m := range s.excludePathMatchers {
+		if m.Match(lowerSlashPath) {
+			return false, ""
 		}
 	}
 
 	// 秘匿ファイルパターンをチェック
 	for _, pattern := range s.config.Include.Names {
 		if strings.Contains(lowerFilename, strings.ToLower(pattern)) {
-			return true
+			return true, "include.names:" + pattern
 		}
 	}
 
 	for _, pattern := range s.config.Include.Extensions {
 		if strings.EqualFold(ext, pattern) {
-			return true
+			return true, "include.extensions:" + pattern
 		}
 	}
 
-	for _, pattern := range s.config.Include.Paths {
-		if matchPathPattern(pattern, lowerPath) {
-			return true
+	for _, m := range s.includePathMatchers {
+		if m.Match(lowerSlashPath) {
+			return true, "include.paths:" + m.raw
 		}
 	}
 
-	return false
+	return false, ""
 }
 
 // matchPathPattern はパスパターンを相対パスの各サブパスに対してマッチングします。
@@ -156,3 +412,80 @@ func matchPathPattern(pattern, path string) bool {
 		path = path[i+1:]
 	}
 }
+
+// scanContent はファイルの内容を行単位でストリーム読みし、設定済みのシグネチャと照合します。
+// バイナリファイルおよびmax_file_sizeを超えるファイルはスキップします。
+func (s *Scanner) scanContent(absPath, relPath string) ([]ContentFinding, error) {
+	maxSize := s.config.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxFileSize
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxSize {
+		return nil, nil
+	}
+
+	f, err := os.Open(absPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	head := make([]byte, binarySniffLen)
+	n, err := f.Read(head)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if bytes.IndexByte(head[:n], 0) >= 0 {
+		// NULバイトを含むファイルはバイナリとみなしスキップする
+		return nil, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var findings []ContentFinding
+	lineScanner := bufio.NewScanner(f)
+	lineScanner.Buffer(make([]byte, 0, 64*1024), maxLineBufferSize)
+
+	lineNum := 0
+	for lineScanner.Scan() {
+		lineNum++
+		line := lineScanner.Text()
+
+		for i := range s.config.Signatures {
+			rule := &s.config.Signatures[i]
+			if rule.Disabled || rule.compiled == nil {
+				continue
+			}
+
+			submatches := rule.compiled.FindStringSubmatch(line)
+			if submatches == nil {
+				continue
+			}
+			match := submatches[0]
+
+			entropy := shannonEntropy(rule.entropyInput(submatches))
+			if rule.Entropy > 0 && entropy < rule.Entropy {
+				continue
+			}
+
+			findings = append(findings, ContentFinding{
+				Path:    relPath,
+				Rule:    rule.Name,
+				Line:    lineNum,
+				Match:   match,
+				Entropy: entropy,
+			})
+		}
+	}
+	if err := lineScanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return findings, nil
+}
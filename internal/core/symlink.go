@@ -0,0 +1,135 @@
+package core
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SymlinkPolicy はシンボリックリンクの辿り方です
+type SymlinkPolicy string
+
+const (
+	// SymlinkSkip はシンボリックリンクを辿りません（既定値。fs.WalkDirの標準動作と同じ）
+	SymlinkSkip SymlinkPolicy = "skip"
+	// SymlinkFollow はシンボリックリンクが指すディレクトリ・ファイルも走査します
+	SymlinkFollow SymlinkPolicy = "follow"
+	// SymlinkFollowSameFS はSymlinkFollowと同様ですが、リンク先がルートと別ファイルシステムの場合は辿りません
+	SymlinkFollowSameFS SymlinkPolicy = "follow-same-fs"
+)
+
+// fileKey はシンボリックリンクの循環を検出するためのファイル識別子です。
+// Unix系ではdev+ino、Windowsではボリュームシリアル番号+ファイルインデックスを使います
+type fileKey struct {
+	device uint64
+	index  uint64
+}
+
+// walkSymlinkDir はシンボリックリンクが指すディレクトリを手動で再帰的に走査し、
+// 候補ファイルのパスをpathsに送出します。fs.WalkDirはシンボリックリンクを
+// 自動的には辿らないため、SymlinkPolicyがfollow/follow-same-fsの場合にここから呼ばれます。
+func (s *Scanner) walkSymlinkDir(dirPath string, info os.FileInfo, paths chan<- string, depth int) error {
+	if depth >= s.maxSymlinkDepth {
+		return nil
+	}
+
+	key, hasKey := fileKeyFor(dirPath, info)
+	if hasKey {
+		if s.visited[key] {
+			return nil
+		}
+		s.visited[key] = true
+	}
+
+	if s.symlinkPolicy == SymlinkFollowSameFS && hasKey {
+		if rootInfo, err := os.Lstat(s.rootDir); err == nil {
+			if rootKey, ok := fileKeyFor(s.rootDir, rootInfo); ok && rootKey.device != key.device {
+				return nil
+			}
+		}
+	}
+
+	// 通常のwalk()と同じく、このディレクトリ直下の無視ファイルを層として積む。
+	// 手動の再帰呼び出しであるため、祖先チェックではなくsave/restoreで親の層に戻す。
+	if !s.config.NoGitignore {
+		savedStack, savedCombined := s.ignoreStack, s.combinedIgnore
+		layer, err := loadIgnoreLayer(s.rootDir, dirPath)
+		if err != nil {
+			return err
+		}
+		if layer != nil {
+			s.ignoreStack = append(s.ignoreStack, *layer)
+			s.combinedIgnore = rebuildCombinedIgnore(s.ignoreStack)
+		}
+		defer func() {
+			s.ignoreStack, s.combinedIgnore = savedStack, savedCombined
+		}()
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && isExcludedDir(name) {
+			continue
+		}
+
+		childPath := filepath.Join(dirPath, name)
+		if !s.config.NoGitignore && isIgnored(s.combinedIgnore, s.rootDir, childPath) {
+			continue
+		}
+
+		hidden := isHidden(childPath, name)
+		if s.hiddenPolicy == HiddenIgnore && hidden {
+			continue
+		}
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			target, statErr := os.Stat(childPath)
+			if statErr != nil {
+				continue // 壊れたリンクは無視する
+			}
+			if target.IsDir() {
+				if err := s.walkSymlinkDir(childPath, target, paths, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			if s.hiddenPolicy != HiddenOnly || hidden {
+				paths <- childPath
+			}
+			continue
+		}
+
+		if entry.IsDir() {
+			childInfo, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			if err := s.walkSymlinkDir(childPath, childInfo, paths, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if s.hiddenPolicy == HiddenOnly && !hidden {
+			continue
+		}
+		paths <- childPath
+	}
+
+	return nil
+}
+
+// isExcludedDir はexcludeDirsに含まれるディレクトリ名かどうかを判定します
+func isExcludedDir(name string) bool {
+	for _, exDir := range excludeDirs {
+		if name == exDir {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package core
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileKeyFor はUnix系でdev+inoからファイル識別子を求めます
+func fileKeyFor(_ string, info os.FileInfo) (fileKey, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, false
+	}
+	return fileKey{device: uint64(stat.Dev), index: stat.Ino}, true
+}
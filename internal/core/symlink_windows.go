@@ -0,0 +1,41 @@
+//go:build windows
+
+package core
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileKeyFor はWindowsでボリュームシリアル番号+ファイルインデックスからファイル識別子を求めます。
+// os.FileInfo.Sys()（Win32FileAttributeData）にはこれらが含まれないため、ハンドルを開き直します
+func fileKeyFor(absPath string, _ os.FileInfo) (fileKey, bool) {
+	pointer, err := syscall.UTF16PtrFromString(absPath)
+	if err != nil {
+		return fileKey{}, false
+	}
+
+	handle, err := syscall.CreateFile(
+		pointer,
+		0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil,
+		syscall.OPEN_EXISTING,
+		syscall.FILE_FLAG_BACKUP_SEMANTICS,
+		0,
+	)
+	if err != nil {
+		return fileKey{}, false
+	}
+	defer syscall.CloseHandle(handle)
+
+	var byHandleInfo syscall.ByHandleFileInformation
+	if err := syscall.GetFileInformationByHandle(handle, &byHandleInfo); err != nil {
+		return fileKey{}, false
+	}
+
+	return fileKey{
+		device: uint64(byHandleInfo.VolumeSerialNumber),
+		index:  uint64(byHandleInfo.FileIndexHigh)<<32 | uint64(byHandleInfo.FileIndexLow),
+	}, true
+}